@@ -1,115 +1,578 @@
 package config
 
 import (
+	"bufio"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/cli-runtime/pkg/printers"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/tools/clientcmd/api/latest"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/scheme"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
+	"k8s.io/kubectl/pkg/util/term"
 )
 
 const (
 	currentContextPropertyName        = `current-context`
 	preferencesColorsPropertyName     = `preferences.colors`
 	preferencesExtensionsPropertyName = `preferences.extensions`
+
+	resetOnlyContexts       = "contexts"
+	resetOnlyClusters       = "clusters"
+	resetOnlyUsers          = "users"
+	resetOnlyPreferences    = "preferences"
+	resetOnlyCurrentContext = "current-context"
+
+	configScopeDefault  = "default"
+	configScopeExplicit = "explicit"
+	configScopeAll      = "all"
+
+	// backupPathGenerated is the value --backup takes when given without a path;
+	// Complete turns it into an empty BackupPath so writeBackup generates one.
+	backupPathGenerated = "<kubeconfig>.bak-<RFC3339 timestamp>"
 )
 
+var resetOnlyValues = []string{resetOnlyContexts, resetOnlyClusters, resetOnlyUsers, resetOnlyPreferences, resetOnlyCurrentContext}
+
+var configScopeValues = []string{configScopeDefault, configScopeExplicit, configScopeAll}
+
 var (
 	resetLong = templates.LongDesc(i18n.T(`Reset all the configs in ths specified kubeconfig file.`))
 
 	resetExample = templates.Examples(`
 		# Reset all configs in kubeconfig
-		kubectl config reset`)
+		kubectl config reset
+
+		# See what reset would do without changing the kubeconfig file
+		kubectl config reset --dry-run=client
+
+		# Reset, keeping a timestamped backup of the previous kubeconfig
+		kubectl config reset --backup
+
+		# Reset everything except the "prod" context and the cluster/user it references
+		kubectl config reset --keep-context=prod
+
+		# Only reset the users, leaving clusters and contexts untouched
+		kubectl config reset --only=users
+
+		# Reset every file in the KUBECONFIG precedence chain, not just the default one
+		kubectl config reset --scope=all
+
+		# Reset and print the resulting (now empty) kubeconfig as YAML
+		kubectl config reset -o yaml
+
+		# Reset non-interactively, e.g. in CI
+		kubectl config reset --force`)
 )
 
+// ResetOptions holds the data and flags needed to run 'config reset'.
+type ResetOptions struct {
+	ConfigAccess clientcmd.ConfigAccess
+	PrintFlags   *genericclioptions.PrintFlags
+
+	Backup     bool
+	BackupPath string
+
+	KeepContexts []string
+	KeepClusters []string
+	KeepUsers    []string
+	Only         string
+	Scope        resetScope
+
+	FileScope string
+
+	// Force skips the interactive confirmation prompt.
+	Force bool
+
+	// OutputRequested is true when the user explicitly set -o/--output, in which
+	// case Run emits the resulting config through PrintFlags instead of the
+	// human-readable summary lines.
+	OutputRequested bool
+
+	DryRunStrategy cmdutil.DryRunStrategy
+
+	// printer is lazily created by printConfig and reused across calls; see printConfig.
+	printer printers.ResourcePrinter
+
+	genericiooptions.IOStreams
+}
+
+// resetScope selects which of the primary config maps deletePrimaryConfigs should touch.
+type resetScope struct {
+	clusters bool
+	contexts bool
+	users    bool
+}
+
+var resetScopeAll = resetScope{clusters: true, contexts: true, users: true}
+
 // NewCmdConfigReset returns a Command instance for 'config reset' sub command
-func NewCmdConfigReset(streams genericiooptions.IOStreams, ConfigAccess clientcmd.ConfigAccess) *cobra.Command {
+func NewCmdConfigReset(streams genericiooptions.IOStreams, configAccess clientcmd.ConfigAccess) *cobra.Command {
+	o := &ResetOptions{
+		ConfigAccess: configAccess,
+		PrintFlags:   genericclioptions.NewPrintFlags("").WithTypeSetter(scheme.Scheme).WithDefaultOutput("yaml"),
+		IOStreams:    streams,
+	}
+
 	cmd := &cobra.Command{
 		Use:     "reset",
 		Short:   i18n.T("Reset all the configs in ths specified kubeconfig file."),
 		Long:    resetLong,
 		Example: resetExample,
 		Run: func(cmd *cobra.Command, args []string) {
-			configFile := ConfigAccess.GetDefaultFilename()
-			if ConfigAccess.IsExplicitFile() {
-				configFile = ConfigAccess.GetExplicitFile()
-			}
+			cmdutil.CheckErr(o.Complete(cmd))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
 
-			config, err := ConfigAccess.GetStartingConfig()
-			cmdutil.CheckErr(err)
+	o.PrintFlags.AddFlags(cmd)
+	cmdutil.AddDryRunFlag(cmd)
 
-			cmdutil.CheckErr(unsetCurrentContext(config, streams.Out, configFile))
-			cmdutil.CheckErr(unsetPreferences(config, streams.Out, configFile))
-			cmdutil.CheckErr(deletePrimaryConfigs(ConfigAccess, *config, streams.Out, configFile))
+	cmd.Flags().StringVar(&o.BackupPath, "backup", "", "Back up the kubeconfig before resetting it. An optional path may be given; if omitted, defaults to \"<kubeconfig>.bak-<RFC3339 timestamp>\".")
+	cmd.Flags().Lookup("backup").NoOptDefVal = backupPathGenerated
 
-		},
-	}
+	cmd.Flags().StringArrayVar(&o.KeepContexts, "keep-context", nil, "Name of a context to preserve instead of resetting; may be repeated. Preserving a context also preserves the cluster and user it references.")
+	cmd.Flags().StringArrayVar(&o.KeepClusters, "keep-cluster", nil, "Name of a cluster to preserve instead of resetting; may be repeated.")
+	cmd.Flags().StringArrayVar(&o.KeepUsers, "keep-user", nil, "Name of a user to preserve instead of resetting; may be repeated.")
+	cmd.Flags().StringVar(&o.Only, "only", "", fmt.Sprintf("Limit the reset to one of %s. If unset, everything is reset.", strings.Join(resetOnlyValues, ", ")))
 
-	flags := genericclioptions.NewPrintFlags("").WithTypeSetter(scheme.Scheme).WithDefaultOutput("yaml")
-	flags.AddFlags(cmd)
+	cmd.Flags().StringVar(&o.FileScope, "scope", configScopeDefault, fmt.Sprintf("Which kubeconfig file(s) to reset: one of %s. %q resets only the default/explicit file (today's behavior); %q requires an explicit --kubeconfig/$KUBECONFIG file; %q resets every file in the $KUBECONFIG loading precedence chain.", strings.Join(configScopeValues, ", "), configScopeDefault, configScopeExplicit, configScopeAll))
+
+	cmd.Flags().BoolVarP(&o.Force, "force", "y", false, "Skip the interactive confirmation prompt and reset immediately.")
 
 	return cmd
 }
 
-func deletePrimaryConfigs(
-	configAccess clientcmd.ConfigAccess,
-	config clientcmdapi.Config,
-	out io.Writer,
-	configFile string,
-) error {
-	var (
-		err            error
-		deletedConfigs = struct {
-			clusters []string
-			contexts []string
-			users    []string
-		}{}
-	)
+// Complete resolves the dry-run strategy, whether a backup or an explicit
+// -o/--output was requested, and the scope of the reset implied by --only.
+func (o *ResetOptions) Complete(cmd *cobra.Command) error {
+	var err error
+	o.DryRunStrategy, err = cmdutil.GetDryRunStrategy(cmd)
+	if err != nil {
+		return err
+	}
 
-	for cluster := range config.Clusters {
-		delete(config.Clusters, cluster)
-		deletedConfigs.clusters = append(deletedConfigs.clusters, cluster)
+	o.Backup = cmd.Flags().Changed("backup")
+	if o.BackupPath == backupPathGenerated {
+		o.BackupPath = ""
 	}
+	o.OutputRequested = cmd.Flags().Changed("output")
 
-	for context := range config.Contexts {
-		delete(config.Contexts, context)
-		deletedConfigs.contexts = append(deletedConfigs.contexts, context)
+	o.Scope, err = resetScopeFor(o.Only)
+	if err != nil {
+		return err
 	}
 
-	for user := range config.AuthInfos {
-		delete(config.AuthInfos, user)
-		deletedConfigs.users = append(deletedConfigs.users, user)
+	switch o.FileScope {
+	case configScopeDefault, configScopeExplicit, configScopeAll:
+	default:
+		return fmt.Errorf("invalid --scope value %q: must be one of %s", o.FileScope, strings.Join(configScopeValues, ", "))
 	}
 
-	err = clientcmd.ModifyConfig(configAccess, config, true)
+	if o.FileScope == configScopeAll && o.BackupPath != "" {
+		return fmt.Errorf("--backup=%s cannot be combined with --scope=all; use --backup without a path to write one backup per file", o.BackupPath)
+	}
+
+	return nil
+}
+
+// resetScopeFor maps the --only flag value to the set of primary config maps
+// deletePrimaryConfigs should touch. An empty value means "everything".
+func resetScopeFor(only string) (resetScope, error) {
+	switch only {
+	case "":
+		return resetScopeAll, nil
+	case resetOnlyClusters:
+		return resetScope{clusters: true}, nil
+	case resetOnlyContexts:
+		return resetScope{contexts: true}, nil
+	case resetOnlyUsers:
+		return resetScope{users: true}, nil
+	case resetOnlyPreferences, resetOnlyCurrentContext:
+		return resetScope{}, nil
+	default:
+		return resetScope{}, fmt.Errorf("invalid --only value %q: must be one of %s", only, strings.Join(resetOnlyValues, ", "))
+	}
+}
+
+// Run resets the kubeconfig file(s) selected by --scope, optionally backing
+// each one up first and optionally only reporting what would change.
+func (o *ResetOptions) Run() error {
+	if err := o.validateKeepNamesExistSomewhere(); err != nil {
+		return err
+	}
+
+	switch o.FileScope {
+	case configScopeAll:
+		return o.runAll()
+	case configScopeExplicit:
+		if !o.ConfigAccess.IsExplicitFile() {
+			return fmt.Errorf("--scope=explicit requires an explicit kubeconfig file (set --kubeconfig or $KUBECONFIG to a single file)")
+		}
+		return o.runSingle(o.ConfigAccess.GetExplicitFile())
+	default:
+		configFile := o.ConfigAccess.GetDefaultFilename()
+		if o.ConfigAccess.IsExplicitFile() {
+			configFile = o.ConfigAccess.GetExplicitFile()
+		}
+		return o.runSingle(configFile)
+	}
+}
+
+// runSingle resets the kubeconfig resolved through ConfigAccess, writing the
+// result back via clientcmd.ModifyConfig so merge/locking semantics match
+// every other `kubectl config` subcommand.
+func (o *ResetOptions) runSingle(configFile string) error {
+	startingConfig, err := o.ConfigAccess.GetStartingConfig()
 	if err != nil {
 		return err
 	}
 
-	_, err = fmt.Fprintf(out, "Deleted all cluster(s) %v from %q\n", deletedConfigs.clusters, configFile)
+	config := startingConfig.DeepCopy()
+	deleted, err := o.resetConfig(config, configFile)
 	if err != nil {
 		return err
 	}
 
-	_, err = fmt.Fprintf(out, "Deleted all context(s) %v from %q\n", deletedConfigs.contexts, configFile)
+	if o.DryRunStrategy != cmdutil.DryRunNone {
+		return o.printConfig(config)
+	}
+
+	if err := o.confirm(configFile, deleted); err != nil {
+		return err
+	}
+
+	if o.Backup {
+		if err := writeBackup(startingConfig, configFile, o.BackupPath); err != nil {
+			return err
+		}
+	}
+
+	if err := clientcmd.ModifyConfig(o.ConfigAccess, *config, true); err != nil {
+		return err
+	}
+
+	if o.OutputRequested {
+		return o.printConfig(config)
+	}
+
+	return reportDeletedConfigs(deleted, o.Scope, o.Out, configFile)
+}
+
+// runAll resets every existing file in the $KUBECONFIG loading precedence
+// chain independently, loading and writing each one directly rather than
+// through the merged view ConfigAccess normally presents.
+func (o *ResetOptions) runAll() error {
+	for _, file := range o.ConfigAccess.GetLoadingPrecedence() {
+		if _, err := os.Stat(file); err != nil {
+			if os.IsNotExist(err) {
+				if _, err := fmt.Fprintf(o.Out, "Skipping %q: file does not exist\n", file); err != nil {
+					return err
+				}
+				continue
+			}
+			return err
+		}
+
+		startingConfig, err := clientcmd.LoadFromFile(file)
+		if err != nil {
+			return err
+		}
+
+		config := startingConfig.DeepCopy()
+		deleted, err := o.resetConfig(config, file)
+		if err != nil {
+			return err
+		}
+
+		if o.DryRunStrategy != cmdutil.DryRunNone {
+			if err := o.printConfig(config); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := o.confirm(file, deleted); err != nil {
+			return err
+		}
+
+		if o.Backup {
+			if err := writeBackup(startingConfig, file, ""); err != nil {
+				return err
+			}
+		}
+
+		if err := clientcmd.WriteToFile(*config, file); err != nil {
+			return err
+		}
+
+		if o.OutputRequested {
+			if err := o.printConfig(config); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := reportDeletedConfigs(deleted, o.Scope, o.Out, file); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resetConfig applies unsetCurrentContext, unsetPreferences and
+// deletePrimaryConfigs to config in place, honoring o.Only/o.Keep*, and
+// returns what deletePrimaryConfigs removed. The per-step human-readable
+// messages are suppressed whenever the final result will instead be emitted
+// as a single printed object (--dry-run or an explicit -o).
+func (o *ResetOptions) resetConfig(config *clientcmdapi.Config, configFile string) (deletedConfigNames, error) {
+	quiet := o.OutputRequested || o.DryRunStrategy != cmdutil.DryRunNone
+	resetAll := o.Only == ""
+
+	if resetAll || o.Only == resetOnlyCurrentContext {
+		if err := unsetCurrentContext(config, o.Out, configFile, quiet); err != nil {
+			return deletedConfigNames{}, err
+		}
+	}
+	if resetAll || o.Only == resetOnlyPreferences {
+		if err := unsetPreferences(config, o.Out, configFile, quiet); err != nil {
+			return deletedConfigNames{}, err
+		}
+	}
+
+	keepClusters, keepContexts, keepUsers := keepSetsFor(config, o.KeepContexts, o.KeepClusters, o.KeepUsers)
+	return deletePrimaryConfigs(config, o.Scope, keepClusters, keepContexts, keepUsers), nil
+}
+
+// validateKeepNamesExistSomewhere rejects --keep-context/--keep-cluster/--keep-user
+// names that don't match anything in the merged kubeconfig view, so a typo results
+// in an error instead of silently resetting the entry the user meant to preserve.
+// It is checked once, against the merge of every file in scope, rather than per
+// file: with --scope=all a kept name commonly lives in only one file of the
+// $KUBECONFIG chain, and requiring it in every file would make --keep-* unusable
+// together with --scope=all.
+func (o *ResetOptions) validateKeepNamesExistSomewhere() error {
+	if len(o.KeepContexts) == 0 && len(o.KeepClusters) == 0 && len(o.KeepUsers) == 0 {
+		return nil
+	}
+
+	mergedConfig, err := o.ConfigAccess.GetStartingConfig()
 	if err != nil {
 		return err
 	}
 
-	_, err = fmt.Fprintf(out, "Deleted all user(s) %v from %q\n", deletedConfigs.users, configFile)
+	return validateKeepNames(mergedConfig, o.KeepContexts, o.KeepClusters, o.KeepUsers)
+}
+
+// validateKeepNames rejects --keep-context/--keep-cluster/--keep-user names
+// that don't match anything in config, so a typo results in an error instead
+// of silently resetting the entry the user meant to preserve.
+func validateKeepNames(config *clientcmdapi.Config, keepContexts, keepClusters, keepUsers []string) error {
+	var unknown []string
+
+	for _, name := range keepContexts {
+		if _, ok := config.Contexts[name]; !ok {
+			unknown = append(unknown, fmt.Sprintf("context %q", name))
+		}
+	}
+	for _, name := range keepClusters {
+		if _, ok := config.Clusters[name]; !ok {
+			unknown = append(unknown, fmt.Sprintf("cluster %q", name))
+		}
+	}
+	for _, name := range keepUsers {
+		if _, ok := config.AuthInfos[name]; !ok {
+			unknown = append(unknown, fmt.Sprintf("user %q", name))
+		}
+	}
+
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown --keep-* name(s): %s", strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
+
+// printConfig renders config through the -o printer, used for --dry-run and
+// -o output. The printer is created once and reused across calls so that
+// printers which track state across PrintObj calls (e.g. the YAML printer's
+// "---" document separator) behave correctly when --scope=all prints more
+// than one config in a single run.
+func (o *ResetOptions) printConfig(config *clientcmdapi.Config) error {
+	if o.printer == nil {
+		printer, err := o.PrintFlags.ToPrinter()
+		if err != nil {
+			return err
+		}
+		o.printer = printer
+	}
+
+	convertedObj, err := latest.Scheme.ConvertToVersion(config, latest.ExternalVersion)
 	if err != nil {
 		return err
 	}
 
+	return o.printer.PrintObj(convertedObj, o.Out)
+}
+
+// confirm lists what a reset of configFile is about to remove and, on a
+// terminal, requires the user to type the file's basename before proceeding.
+// It is a no-op when --force/-y was given, and refuses to run when stdin
+// isn't a terminal and --force wasn't given (Run never calls it in dry-run mode).
+func (o *ResetOptions) confirm(configFile string, deleted deletedConfigNames) error {
+	if o.Force {
+		return nil
+	}
+
+	if !term.IsTerminal(o.In) {
+		return fmt.Errorf("resetting %q would remove cluster(s) %v, context(s) %v and user(s) %v; rerun with --force/-y to proceed without a TTY", configFile, deleted.clusters, deleted.contexts, deleted.users)
+	}
+
+	return o.promptConfirmation(configFile, deleted)
+}
+
+// promptConfirmation prints what will be removed from configFile and reads a
+// confirmation response from o.In, which must equal the file's basename for
+// the reset to proceed. Split out of confirm so the prompt/compare logic can
+// be exercised without a real terminal.
+func (o *ResetOptions) promptConfirmation(configFile string, deleted deletedConfigNames) error {
+	if _, err := fmt.Fprintf(o.Out, "This will remove cluster(s) %v, context(s) %v and user(s) %v from %q.\n", deleted.clusters, deleted.contexts, deleted.users, configFile); err != nil {
+		return err
+	}
+
+	basename := filepath.Base(configFile)
+	if _, err := fmt.Fprintf(o.Out, "Type %q to confirm, anything else to abort: ", basename); err != nil {
+		return err
+	}
+
+	response, err := bufio.NewReader(o.In).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	if strings.TrimSpace(response) != basename {
+		return fmt.Errorf("confirmation text did not match %q; aborting reset of %q", basename, configFile)
+	}
+
+	return nil
+}
+
+// keepSetsFor expands the user-provided --keep-cluster/--keep-context/--keep-user
+// names into name sets, transitively preserving the cluster and user referenced
+// by each kept context.
+func keepSetsFor(config *clientcmdapi.Config, keepContexts, keepClusters, keepUsers []string) (clusters, contexts, users map[string]bool) {
+	clusters = toNameSet(keepClusters)
+	contexts = toNameSet(keepContexts)
+	users = toNameSet(keepUsers)
+
+	for name := range contexts {
+		if context, ok := config.Contexts[name]; ok {
+			clusters[context.Cluster] = true
+			users[context.AuthInfo] = true
+		}
+	}
+
+	return clusters, contexts, users
+}
+
+func toNameSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+// writeBackup persists config to backupPath (or a generated "<configFile>.bak-<RFC3339>"
+// path when backupPath is empty) so a reset can be undone by hand.
+func writeBackup(config *clientcmdapi.Config, configFile, backupPath string) error {
+	if backupPath == "" {
+		backupPath = fmt.Sprintf("%s.bak-%s", configFile, time.Now().Format(time.RFC3339))
+	}
+
+	return clientcmd.WriteToFile(*config, backupPath)
+}
+
+// deletedConfigNames records the names removed from each map of config by deletePrimaryConfigs.
+type deletedConfigNames struct {
+	clusters []string
+	contexts []string
+	users    []string
+}
+
+// deletePrimaryConfigs clears the clusters, contexts and users selected by scope from
+// config in place, skipping any name present in the corresponding keep set.
+func deletePrimaryConfigs(config *clientcmdapi.Config, scope resetScope, keepClusters, keepContexts, keepUsers map[string]bool) deletedConfigNames {
+	var deleted deletedConfigNames
+
+	if scope.clusters {
+		for cluster := range config.Clusters {
+			if keepClusters[cluster] {
+				continue
+			}
+			delete(config.Clusters, cluster)
+			deleted.clusters = append(deleted.clusters, cluster)
+		}
+	}
+
+	if scope.contexts {
+		for context := range config.Contexts {
+			if keepContexts[context] {
+				continue
+			}
+			delete(config.Contexts, context)
+			deleted.contexts = append(deleted.contexts, context)
+		}
+	}
+
+	if scope.users {
+		for user := range config.AuthInfos {
+			if keepUsers[user] {
+				continue
+			}
+			delete(config.AuthInfos, user)
+			deleted.users = append(deleted.users, user)
+		}
+	}
+
+	return deleted
+}
+
+// reportDeletedConfigs prints a human-readable summary of what deletePrimaryConfigs removed,
+// limited to the maps selected by scope.
+func reportDeletedConfigs(deleted deletedConfigNames, scope resetScope, out io.Writer, configFile string) error {
+	if scope.clusters {
+		if _, err := fmt.Fprintf(out, "Deleted cluster(s) %v from %q\n", deleted.clusters, configFile); err != nil {
+			return err
+		}
+	}
+
+	if scope.contexts {
+		if _, err := fmt.Fprintf(out, "Deleted context(s) %v from %q\n", deleted.contexts, configFile); err != nil {
+			return err
+		}
+	}
+
+	if scope.users {
+		if _, err := fmt.Fprintf(out, "Deleted user(s) %v from %q\n", deleted.users, configFile); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -117,6 +580,7 @@ func unsetCurrentContext(
 	config *clientcmdapi.Config,
 	out io.Writer,
 	configFile string,
+	quiet bool,
 ) error {
 	steps, err := newNavigationSteps(currentContextPropertyName)
 	if err != nil {
@@ -128,6 +592,10 @@ func unsetCurrentContext(
 		return err
 	}
 
+	if quiet {
+		return nil
+	}
+
 	_, err = fmt.Fprintf(out, "Property %q unset from %q\n", currentContextPropertyName, configFile)
 	if err != nil {
 		return err
@@ -140,6 +608,7 @@ func unsetPreferences(
 	config *clientcmdapi.Config,
 	out io.Writer,
 	configFile string,
+	quiet bool,
 ) error {
 	steps, err := newNavigationSteps(preferencesColorsPropertyName)
 	if err != nil {
@@ -161,6 +630,10 @@ func unsetPreferences(
 		return err
 	}
 
+	if quiet {
+		return nil
+	}
+
 	_, err = fmt.Fprintf(out, "All preferences are unset from %q\n", configFile)
 	if err != nil {
 		return err
@@ -0,0 +1,271 @@
+package config
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/scheme"
+)
+
+// fakeConfigAccess is a minimal clientcmd.ConfigAccess whose loading
+// precedence is whatever the test sets it to, so runAll can be exercised
+// against fixed temp files instead of the real $KUBECONFIG chain.
+// startingConfig stands in for the real merged view clientcmd.PathOptions
+// would build from every file in precedence; it defaults to an empty config.
+type fakeConfigAccess struct {
+	precedence     []string
+	startingConfig *clientcmdapi.Config
+}
+
+func (f *fakeConfigAccess) GetLoadingPrecedence() []string { return f.precedence }
+func (f *fakeConfigAccess) GetStartingConfig() (*clientcmdapi.Config, error) {
+	if f.startingConfig != nil {
+		return f.startingConfig, nil
+	}
+	return clientcmdapi.NewConfig(), nil
+}
+func (f *fakeConfigAccess) GetDefaultFilename() string { return "" }
+func (f *fakeConfigAccess) IsExplicitFile() bool       { return false }
+func (f *fakeConfigAccess) GetExplicitFile() string    { return "" }
+
+func newTestConfig() *clientcmdapi.Config {
+	config := clientcmdapi.NewConfig()
+	config.Clusters["prod-cluster"] = &clientcmdapi.Cluster{Server: "https://prod.example.com"}
+	config.Clusters["dev-cluster"] = &clientcmdapi.Cluster{Server: "https://dev.example.com"}
+	config.AuthInfos["prod-admin"] = &clientcmdapi.AuthInfo{Token: "prod-token"}
+	config.AuthInfos["dev-admin"] = &clientcmdapi.AuthInfo{Token: "dev-token"}
+	config.Contexts["prod"] = &clientcmdapi.Context{Cluster: "prod-cluster", AuthInfo: "prod-admin"}
+	config.Contexts["dev"] = &clientcmdapi.Context{Cluster: "dev-cluster", AuthInfo: "dev-admin"}
+	config.CurrentContext = "prod"
+	return config
+}
+
+func writeTestConfig(t *testing.T, path string, config *clientcmdapi.Config) {
+	t.Helper()
+	if err := clientcmd.WriteToFile(*config, path); err != nil {
+		t.Fatalf("writing test kubeconfig: %v", err)
+	}
+}
+
+func TestResetScopeFor(t *testing.T) {
+	tests := []struct {
+		only    string
+		want    resetScope
+		wantErr bool
+	}{
+		{only: "", want: resetScopeAll},
+		{only: resetOnlyClusters, want: resetScope{clusters: true}},
+		{only: resetOnlyContexts, want: resetScope{contexts: true}},
+		{only: resetOnlyUsers, want: resetScope{users: true}},
+		{only: resetOnlyPreferences, want: resetScope{}},
+		{only: resetOnlyCurrentContext, want: resetScope{}},
+		{only: "bogus", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := resetScopeFor(tt.only)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("resetScopeFor(%q): expected an error, got none", tt.only)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("resetScopeFor(%q): unexpected error: %v", tt.only, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("resetScopeFor(%q) = %+v, want %+v", tt.only, got, tt.want)
+		}
+	}
+}
+
+func TestKeepSetsFor(t *testing.T) {
+	config := newTestConfig()
+
+	clusters, contexts, users := keepSetsFor(config, []string{"prod"}, nil, nil)
+
+	if !contexts["prod"] {
+		t.Errorf("expected %q to be in the kept-contexts set", "prod")
+	}
+	if !clusters["prod-cluster"] {
+		t.Errorf("keeping context %q should transitively keep cluster %q", "prod", "prod-cluster")
+	}
+	if !users["prod-admin"] {
+		t.Errorf("keeping context %q should transitively keep user %q", "prod", "prod-admin")
+	}
+	if clusters["dev-cluster"] || users["dev-admin"] {
+		t.Errorf("unrelated dev cluster/user should not be kept")
+	}
+}
+
+func TestValidateKeepNames(t *testing.T) {
+	config := newTestConfig()
+
+	if err := validateKeepNames(config, []string{"prod"}, []string{"dev-cluster"}, []string{"prod-admin"}); err != nil {
+		t.Errorf("unexpected error for known names: %v", err)
+	}
+
+	err := validateKeepNames(config, []string{"pord"}, nil, nil)
+	if err == nil {
+		t.Fatal("expected an error for an unknown --keep-context name, got none")
+	}
+	if !strings.Contains(err.Error(), `"pord"`) {
+		t.Errorf("error %q should name the unrecognized context", err)
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	deleted := deletedConfigNames{clusters: []string{"prod-cluster"}}
+
+	t.Run("force bypasses the prompt", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		o := &ResetOptions{
+			Force:     true,
+			IOStreams: genericiooptions.IOStreams{In: strings.NewReader(""), Out: out},
+		}
+
+		if err := o.confirm("/tmp/config", deleted); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.Len() != 0 {
+			t.Errorf("expected no prompt output with --force, got %q", out.String())
+		}
+	})
+
+	t.Run("refuses without a terminal or --force", func(t *testing.T) {
+		o := &ResetOptions{
+			IOStreams: genericiooptions.IOStreams{In: strings.NewReader(""), Out: &bytes.Buffer{}},
+		}
+
+		err := o.confirm("/tmp/config", deleted)
+		if err == nil {
+			t.Fatal("expected an error when stdin isn't a terminal and --force wasn't given")
+		}
+		if !strings.Contains(err.Error(), "--force") {
+			t.Errorf("error %q should mention --force as the bypass", err)
+		}
+	})
+}
+
+func TestPromptConfirmation(t *testing.T) {
+	deleted := deletedConfigNames{clusters: []string{"prod-cluster"}, contexts: []string{"prod"}}
+	configFile := filepath.Join("home", "user", ".kube", "config")
+
+	t.Run("matching basename proceeds", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		o := &ResetOptions{IOStreams: genericiooptions.IOStreams{In: strings.NewReader("config\n"), Out: out}}
+
+		if err := o.promptConfirmation(configFile, deleted); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(out.String(), "prod-cluster") {
+			t.Errorf("prompt output %q should list what will be removed", out.String())
+		}
+	})
+
+	t.Run("mismatched response aborts", func(t *testing.T) {
+		o := &ResetOptions{IOStreams: genericiooptions.IOStreams{In: strings.NewReader("nope\n"), Out: &bytes.Buffer{}}}
+
+		if err := o.promptConfirmation(configFile, deleted); err == nil {
+			t.Fatal("expected an error for a non-matching confirmation response")
+		}
+	})
+}
+
+func TestRunAllAcrossPrecedenceChain(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first")
+	second := filepath.Join(dir, "second")
+	missing := filepath.Join(dir, "missing")
+
+	writeTestConfig(t, first, newTestConfig())
+	writeTestConfig(t, second, newTestConfig())
+
+	out := &bytes.Buffer{}
+	o := &ResetOptions{
+		ConfigAccess:   &fakeConfigAccess{precedence: []string{first, second, missing}},
+		PrintFlags:     genericclioptions.NewPrintFlags("").WithTypeSetter(scheme.Scheme).WithDefaultOutput("yaml"),
+		FileScope:      configScopeAll,
+		Scope:          resetScopeAll,
+		DryRunStrategy: cmdutil.DryRunClient,
+		IOStreams:      genericiooptions.IOStreams{In: strings.NewReader(""), Out: out},
+	}
+
+	if err := o.Run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(out.String(), "does not exist") {
+		t.Errorf("expected the missing precedence file to be reported as skipped, got %q", out.String())
+	}
+	if n := strings.Count(out.String(), "apiVersion:"); n != 2 {
+		t.Errorf("expected one printed document per existing file (2), got %d in %q", n, out.String())
+	}
+	if !strings.Contains(out.String(), "---") {
+		t.Errorf("expected a \"---\" document separator between the two printed configs, got %q", out.String())
+	}
+}
+
+// TestRunAllKeepPresentInOnlyOneFile covers the $KUBECONFIG precedence-chain
+// scenario from chunk0-2/chunk0-3: --keep-context names a context that only
+// exists in one file of the chain. Validation must succeed against the merged
+// view, and the kept context must survive only in the file that actually has it.
+func TestRunAllKeepPresentInOnlyOneFile(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first")
+	second := filepath.Join(dir, "second")
+
+	devOnly := clientcmdapi.NewConfig()
+	devOnly.Clusters["dev-cluster"] = &clientcmdapi.Cluster{Server: "https://dev.example.com"}
+	devOnly.AuthInfos["dev-admin"] = &clientcmdapi.AuthInfo{Token: "dev-token"}
+	devOnly.Contexts["dev"] = &clientcmdapi.Context{Cluster: "dev-cluster", AuthInfo: "dev-admin"}
+	devOnly.CurrentContext = "dev"
+
+	writeTestConfig(t, first, newTestConfig())
+	writeTestConfig(t, second, devOnly)
+
+	out := &bytes.Buffer{}
+	o := &ResetOptions{
+		ConfigAccess: &fakeConfigAccess{
+			precedence:     []string{first, second},
+			startingConfig: newTestConfig(),
+		},
+		PrintFlags:   genericclioptions.NewPrintFlags("").WithTypeSetter(scheme.Scheme).WithDefaultOutput("yaml"),
+		FileScope:    configScopeAll,
+		Scope:        resetScopeAll,
+		KeepContexts: []string{"prod"},
+		Force:        true,
+		IOStreams:    genericiooptions.IOStreams{In: strings.NewReader(""), Out: out},
+	}
+
+	if err := o.Run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	gotFirst, err := clientcmd.LoadFromFile(first)
+	if err != nil {
+		t.Fatalf("loading %q: %v", first, err)
+	}
+	if _, ok := gotFirst.Contexts["prod"]; !ok {
+		t.Errorf("expected kept context %q to survive in %q", "prod", first)
+	}
+	if _, ok := gotFirst.Contexts["dev"]; ok {
+		t.Errorf("expected non-kept context %q to be removed from %q", "dev", first)
+	}
+
+	gotSecond, err := clientcmd.LoadFromFile(second)
+	if err != nil {
+		t.Fatalf("loading %q: %v", second, err)
+	}
+	if len(gotSecond.Contexts) != 0 {
+		t.Errorf("expected %q (which never had %q) to be fully reset, got contexts %v", second, "prod", gotSecond.Contexts)
+	}
+}